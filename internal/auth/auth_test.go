@@ -0,0 +1,135 @@
+package auth
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestParseToken(t *testing.T) {
+	secret := []byte("test-secret")
+
+	valid, err := GenerateToken(secret, "uuid-1", RoleUser, time.Hour)
+	if err != nil {
+		t.Fatalf("GenerateToken: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		secret  []byte
+		token   func() string
+		wantErr bool
+	}{
+		{
+			name:   "valid token",
+			secret: secret,
+			token:  func() string { return valid },
+		},
+		{
+			name:    "wrong secret",
+			secret:  []byte("different-secret"),
+			token:   func() string { return valid },
+			wantErr: true,
+		},
+		{
+			name:    "malformed token",
+			secret:  secret,
+			token:   func() string { return "not-a-jwt" },
+			wantErr: true,
+		},
+		{
+			name:   "expired token",
+			secret: secret,
+			token: func() string {
+				expired, err := GenerateToken(secret, "uuid-1", RoleUser, -time.Hour)
+				if err != nil {
+					t.Fatalf("GenerateToken: %v", err)
+				}
+				return expired
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseToken(tt.secret, tt.token())
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseToken() err = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	secret := []byte("test-secret")
+	token, err := GenerateToken(secret, "uuid-1", RoleAdmin, time.Hour)
+	if err != nil {
+		t.Fatalf("GenerateToken: %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		header     string
+		wantStatus int
+		wantClaims bool
+	}{
+		{
+			name:       "missing header",
+			header:     "",
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "malformed header",
+			header:     token,
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "invalid token",
+			header:     "Bearer not-a-jwt",
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "valid token",
+			header:     "Bearer " + token,
+			wantStatus: http.StatusOK,
+			wantClaims: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var sawClaims bool
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				_, sawClaims = FromContext(r.Context())
+				w.WriteHeader(http.StatusOK)
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.header != "" {
+				req.Header.Set("Authorization", tt.header)
+			}
+			rec := httptest.NewRecorder()
+
+			Middleware(secret)(next).ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if sawClaims != tt.wantClaims {
+				t.Errorf("claims injected = %v, want %v", sawClaims, tt.wantClaims)
+			}
+			if tt.wantStatus != http.StatusOK {
+				var body errorEnvelope
+				if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+					t.Fatalf("response body is not a JSON errorEnvelope: %v", err)
+				}
+				if body.Error == "" {
+					t.Error("errorEnvelope.Error is empty")
+				}
+			}
+		})
+	}
+}
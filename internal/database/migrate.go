@@ -0,0 +1,95 @@
+package database
+
+import (
+	"embed"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+)
+
+//go:embed migrations
+var migrationFiles embed.FS
+
+const schemaMigrationsTable = `CREATE TABLE IF NOT EXISTS schema_migrations (version VARCHAR(255) PRIMARY KEY);`
+
+// migrate applies every *.up.sql file embedded under migrations/<driverName>
+// that isn't yet recorded in schema_migrations, in filename order, so schema
+// changes are tracked instead of relying on CREATE TABLE IF NOT EXISTS alone.
+func migrate(db *sqlx.DB, driverName string) error {
+	if _, err := db.Exec(schemaMigrationsTable); err != nil {
+		return err
+	}
+
+	dir := path.Join("migrations", driverName)
+	entries, err := fs.ReadDir(migrationFiles, dir)
+	if err != nil {
+		return fmt.Errorf("database: no migrations for driver %q: %w", driverName, err)
+	}
+
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		if !entry.IsDir() && strings.HasSuffix(entry.Name(), ".up.sql") {
+			names = append(names, entry.Name())
+		}
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		var applied int
+		if err = db.Get(&applied, db.Rebind(`SELECT COUNT(*) FROM schema_migrations WHERE version = ?`), name); err != nil {
+			return err
+		}
+		if applied > 0 {
+			continue
+		}
+
+		contents, err := migrationFiles.ReadFile(path.Join(dir, name))
+		if err != nil {
+			return err
+		}
+		for _, stmt := range splitStatements(string(contents)) {
+			if _, err = db.Exec(stmt); err != nil {
+				if isDuplicateColumnErr(err) {
+					continue
+				}
+				return fmt.Errorf("database: migration %s: %w", name, err)
+			}
+		}
+		if _, err = db.Exec(db.Rebind(`INSERT INTO schema_migrations (version) VALUES (?)`), name); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// isDuplicateColumnErr reports whether err is sqlite3's "duplicate column
+// name" error, raised when an `ALTER TABLE ... ADD COLUMN` targets a column
+// that already exists. SQLite has no `ADD COLUMN IF NOT EXISTS`, so a
+// migration that must stay idempotent against both a fresh table (which
+// already has the column) and a genuinely legacy one (which doesn't) backs
+// the column in unconditionally and relies on this check to ignore the
+// no-op case. MySQL and Postgres express the same idempotency with
+// `IF NOT EXISTS` directly in the DDL and never hit this path.
+func isDuplicateColumnErr(err error) bool {
+	return err != nil && strings.Contains(err.Error(), "duplicate column name")
+}
+
+// splitStatements breaks a migration file into its individual `;`-terminated
+// statements. go-sql-driver/mysql rejects multi-statement Exec calls unless
+// the DSN opts into multiStatements=true, so migrations run one statement at
+// a time to stay portable across drivers without requiring that flag.
+func splitStatements(sql string) []string {
+	parts := strings.Split(sql, ";")
+	statements := make([]string, 0, len(parts))
+	for _, part := range parts {
+		if stmt := strings.TrimSpace(part); stmt != "" {
+			statements = append(statements, stmt+";")
+		}
+	}
+	return statements
+}
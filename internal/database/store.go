@@ -0,0 +1,230 @@
+// Package database provides a pluggable persistence layer for the users
+// API. A Store can be backed by SQLite, MySQL or Postgres; schema changes
+// are tracked as versioned migrations instead of relying on ad-hoc DDL at
+// startup.
+package database
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/jmoiron/sqlx/reflectx"
+	"github.com/lib/pq"
+	"github.com/mattn/go-sqlite3"
+)
+
+// DefaultRole is assigned to a User created without an explicit role.
+const DefaultRole = "user"
+
+// ErrDuplicateEmail is returned by CreateUser when the users.email unique
+// constraint rejects the insert, so handlers can turn it into a 409
+// instead of a generic 500.
+var ErrDuplicateEmail = errors.New("database: email already registered")
+
+// Store is the persistence interface used by the HTTP handlers. Every
+// driver-specific implementation satisfies it identically, so handlers
+// never need to know which database they're talking to.
+type Store interface {
+	CreateUser(user *User) error
+	GetUser(uuid string) (*User, error)
+	GetUserByEmail(email string) (*User, error)
+	ListUsers(params ListParams) ([]User, error)
+	CountUsers(params ListParams) (int, error)
+	UpdateUser(user *User) error
+	DeleteUser(uuid string) error
+	Close() error
+}
+
+const (
+	sqlInsertUser      = `INSERT INTO users (uuid, username, age, email, phone, password_hash, role, createAt) VALUES (?, ?, ?, ?, ?, ?, ?, ?);`
+	sqlSelectUsersBase = `SELECT uuid, username, age, email, phone, password_hash, role, createAt FROM users`
+	sqlCountUsersBase  = `SELECT COUNT(*) FROM users`
+	sqlSelectUser      = `SELECT uuid, username, age, email, phone, password_hash, role, createAt FROM users WHERE uuid = ?;`
+	sqlSelectUserEmail = `SELECT uuid, username, age, email, phone, password_hash, role, createAt FROM users WHERE email = ?;`
+	sqlDeleteUser      = `DELETE FROM users WHERE uuid = ?;`
+	sqlUpdateUser      = `UPDATE users SET username = ?, age = ?, email = ?, phone = ? WHERE uuid = ?;`
+)
+
+// sqlStore is a Store backed by database/sql through sqlx. The same query
+// set works across sqlite3, mysql and postgres drivers: sqlx.Rebind adapts
+// the `?` placeholders to each driver's native style. With uuid as the
+// primary key, every CRUD operation is a single statement, so there's no
+// dialect-specific branching left to do.
+type sqlStore struct {
+	db *sqlx.DB
+}
+
+// Open connects to driverName/dataSourceName, applies any pending
+// migrations for that driver, and returns a ready-to-use Store.
+func Open(driverName, dataSourceName string) (Store, error) {
+	db, err := sqlx.Open(driverName, dataSourceName)
+	if err != nil {
+		return nil, err
+	}
+	if err = db.Ping(); err != nil {
+		return nil, err
+	}
+	if driverName == "postgres" {
+		// Postgres folds unquoted identifiers such as createAt to lower case.
+		db.Mapper = reflectx.NewMapperTagFunc("db", strings.ToLower, strings.ToLower)
+	}
+	if err = migrate(db, driverName); err != nil {
+		return nil, err
+	}
+
+	return &sqlStore{db: db}, nil
+}
+
+func (s *sqlStore) Close() error {
+	return s.db.Close()
+}
+
+func (s *sqlStore) CreateUser(user *User) error {
+	user.UUID = uuid.New().String()
+	if user.CreateAt == "" {
+		user.CreateAt = time.Now().Format(time.RFC3339)
+	}
+	if user.Role == "" {
+		user.Role = DefaultRole
+	}
+
+	if _, err := s.db.Exec(s.db.Rebind(sqlInsertUser), user.UUID, user.Username, user.Age, user.Email, user.Phone, user.PasswordHash, user.Role, user.CreateAt); err != nil {
+		if isDuplicateEmailErr(err) {
+			return ErrDuplicateEmail
+		}
+		return err
+	}
+	return nil
+}
+
+func (s *sqlStore) GetUser(uid string) (*User, error) {
+	var user User
+	if err := s.db.Get(&user, s.db.Rebind(sqlSelectUser), uid); err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+func (s *sqlStore) GetUserByEmail(email string) (*User, error) {
+	var user User
+	if err := s.db.Get(&user, s.db.Rebind(sqlSelectUserEmail), email); err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+func (s *sqlStore) ListUsers(params ListParams) ([]User, error) {
+	where, args := buildUserFilters(params)
+
+	query := s.db.Rebind(sqlSelectUsersBase + where +
+		fmt.Sprintf(" ORDER BY %s %s LIMIT ? OFFSET ?", params.SortColumn, params.SortOrder))
+	args = append(args, params.Limit, params.Offset)
+
+	rows, err := s.db.Queryx(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	users := make([]User, 0)
+	for rows.Next() {
+		var user User
+		if err = rows.StructScan(&user); err != nil {
+			return nil, err
+		}
+		users = append(users, user)
+	}
+
+	return users, rows.Err()
+}
+
+func (s *sqlStore) CountUsers(params ListParams) (int, error) {
+	where, args := buildUserFilters(params)
+
+	var total int
+	if err := s.db.Get(&total, s.db.Rebind(sqlCountUsersBase+where), args...); err != nil {
+		return 0, err
+	}
+
+	return total, nil
+}
+
+func (s *sqlStore) UpdateUser(user *User) error {
+	result, err := s.db.Exec(s.db.Rebind(sqlUpdateUser), user.Username, user.Age, user.Email, user.Phone, user.UUID)
+	if err != nil {
+		if isDuplicateEmailErr(err) {
+			return ErrDuplicateEmail
+		}
+		return err
+	}
+	return rowsAffectedOrNoRows(result)
+}
+
+func (s *sqlStore) DeleteUser(uid string) error {
+	result, err := s.db.Exec(s.db.Rebind(sqlDeleteUser), uid)
+	if err != nil {
+		return err
+	}
+	return rowsAffectedOrNoRows(result)
+}
+
+// rowsAffectedOrNoRows returns sql.ErrNoRows when result touched zero rows,
+// so a PUT/DELETE on a uuid that doesn't exist surfaces as a 404 instead of
+// silently succeeding.
+func rowsAffectedOrNoRows(result sql.Result) error {
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// isDuplicateEmailErr reports whether err is the unique-constraint
+// violation each driver raises for users.email, translated from that
+// driver's own error type.
+func isDuplicateEmailErr(err error) bool {
+	var sqliteErr sqlite3.Error
+	if errors.As(err, &sqliteErr) {
+		return sqliteErr.ExtendedCode == sqlite3.ErrConstraintUnique
+	}
+
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) {
+		return mysqlErr.Number == 1062
+	}
+
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		return pqErr.Code == "23505"
+	}
+
+	return false
+}
+
+// buildUserFilters renders the WHERE clause and its bound arguments shared
+// by ListUsers and CountUsers. Column names are interpolated as given
+// (parseUserListParams admits only FilterableUserColumns); filter values
+// are always passed as bound parameters.
+func buildUserFilters(params ListParams) (string, []any) {
+	if len(params.Filters) == 0 {
+		return "", nil
+	}
+
+	conditions := make([]string, 0, len(params.Filters))
+	args := make([]any, 0, len(params.Filters))
+	for column, value := range params.Filters {
+		conditions = append(conditions, fmt.Sprintf("%s = ?", column))
+		args = append(args, value)
+	}
+
+	return " WHERE " + strings.Join(conditions, " AND "), args
+}
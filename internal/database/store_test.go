@@ -0,0 +1,168 @@
+package database
+
+import (
+	"database/sql"
+	"errors"
+	"testing"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+func TestBuildUserFilters(t *testing.T) {
+	tests := []struct {
+		name      string
+		params    ListParams
+		wantWhere string
+		wantArgs  []any
+	}{
+		{
+			name:      "no filters",
+			params:    ListParams{},
+			wantWhere: "",
+			wantArgs:  nil,
+		},
+		{
+			name:      "single filter",
+			params:    ListParams{Filters: map[string]string{"email": "a@example.com"}},
+			wantWhere: " WHERE email = ?",
+			wantArgs:  []any{"a@example.com"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			where, args := buildUserFilters(tt.params)
+			if where != tt.wantWhere {
+				t.Errorf("where = %q, want %q", where, tt.wantWhere)
+			}
+			if len(args) != len(tt.wantArgs) {
+				t.Fatalf("args = %v, want %v", args, tt.wantArgs)
+			}
+			for i := range args {
+				if args[i] != tt.wantArgs[i] {
+					t.Errorf("args[%d] = %v, want %v", i, args[i], tt.wantArgs[i])
+				}
+			}
+		})
+	}
+}
+
+func TestBuildUserFilters_MultipleFilters(t *testing.T) {
+	where, args := buildUserFilters(ListParams{Filters: map[string]string{
+		"email":    "a@example.com",
+		"username": "alice",
+	}})
+
+	if len(args) != 2 {
+		t.Fatalf("expected 2 bound args, got %d: %v", len(args), args)
+	}
+	if !(contains(where, "email = ?") && contains(where, "username = ?") && contains(where, " AND ")) {
+		t.Errorf("where clause %q missing expected conditions", where)
+	}
+}
+
+func contains(s, substr string) bool {
+	return len(s) >= len(substr) && (func() bool {
+		for i := 0; i+len(substr) <= len(s); i++ {
+			if s[i:i+len(substr)] == substr {
+				return true
+			}
+		}
+		return false
+	})()
+}
+
+// newTestStore opens an isolated in-memory SQLite store, applying migrations
+// the same way Open does in production.
+func newTestStore(t *testing.T) Store {
+	t.Helper()
+
+	store, err := Open("sqlite3", "file:"+t.Name()+"?mode=memory&cache=shared&_foreign_keys=1")
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	t.Cleanup(func() { store.Close() })
+
+	return store
+}
+
+func TestSqlStore_CRUDRoundTrip(t *testing.T) {
+	store := newTestStore(t)
+
+	user := &User{Username: "alice", Email: "alice@example.com", Age: 30}
+	if err := store.CreateUser(user); err != nil {
+		t.Fatalf("CreateUser: %v", err)
+	}
+	if user.UUID == "" {
+		t.Fatal("CreateUser did not assign a UUID")
+	}
+	if user.Role != DefaultRole {
+		t.Errorf("Role = %q, want %q", user.Role, DefaultRole)
+	}
+
+	got, err := store.GetUser(user.UUID)
+	if err != nil {
+		t.Fatalf("GetUser: %v", err)
+	}
+	if got.Email != user.Email {
+		t.Errorf("GetUser email = %q, want %q", got.Email, user.Email)
+	}
+
+	got, err = store.GetUserByEmail(user.Email)
+	if err != nil {
+		t.Fatalf("GetUserByEmail: %v", err)
+	}
+	if got.UUID != user.UUID {
+		t.Errorf("GetUserByEmail uuid = %q, want %q", got.UUID, user.UUID)
+	}
+
+	got.Username = "alice2"
+	if err = store.UpdateUser(got); err != nil {
+		t.Fatalf("UpdateUser: %v", err)
+	}
+	got, err = store.GetUser(user.UUID)
+	if err != nil {
+		t.Fatalf("GetUser after update: %v", err)
+	}
+	if got.Username != "alice2" {
+		t.Errorf("Username after update = %q, want %q", got.Username, "alice2")
+	}
+
+	if err = store.DeleteUser(user.UUID); err != nil {
+		t.Fatalf("DeleteUser: %v", err)
+	}
+	if _, err = store.GetUser(user.UUID); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("GetUser after delete = %v, want sql.ErrNoRows", err)
+	}
+}
+
+func TestSqlStore_CreateUser_DuplicateEmail(t *testing.T) {
+	store := newTestStore(t)
+
+	first := &User{Username: "alice", Email: "alice@example.com"}
+	if err := store.CreateUser(first); err != nil {
+		t.Fatalf("CreateUser: %v", err)
+	}
+
+	second := &User{Username: "alice-again", Email: "alice@example.com"}
+	if err := store.CreateUser(second); !errors.Is(err, ErrDuplicateEmail) {
+		t.Errorf("CreateUser duplicate email = %v, want ErrDuplicateEmail", err)
+	}
+}
+
+func TestSqlStore_UpdateUser_NonexistentUUID(t *testing.T) {
+	store := newTestStore(t)
+
+	err := store.UpdateUser(&User{UUID: "does-not-exist", Username: "ghost"})
+	if !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("UpdateUser on missing uuid = %v, want sql.ErrNoRows", err)
+	}
+}
+
+func TestSqlStore_DeleteUser_NonexistentUUID(t *testing.T) {
+	store := newTestStore(t)
+
+	if err := store.DeleteUser("does-not-exist"); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("DeleteUser on missing uuid = %v, want sql.ErrNoRows", err)
+	}
+}
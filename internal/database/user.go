@@ -0,0 +1,48 @@
+package database
+
+// User is the persisted shape of an account row. The validate tags are
+// enforced by AddUserHandler and UpdateUserHandler; RegisterHandler and the
+// initial admin seed store a User without running them.
+type User struct {
+	UUID         string `json:"uuid" db:"uuid"`
+	Username     string `json:"username" db:"username" validate:"required,min=3,max=50"`
+	Email        string `json:"email" db:"email" validate:"required,email"`
+	Phone        string `json:"phone" db:"phone" validate:"omitempty,e164"`
+	Age          int    `json:"age" db:"age" validate:"gte=0,lte=150"`
+	Role         string `json:"role" db:"role"`
+	PasswordHash string `json:"-" db:"password_hash"`
+	CreateAt     string `json:"createAt" db:"createAt"`
+}
+
+const (
+	DefaultListLimit = 50
+	MaxListLimit     = 500
+)
+
+// SortableUserColumns whitelists the columns parseUserListParams accepts as
+// sort_column. ListUsers interpolates ListParams.SortColumn into its ORDER BY
+// clause as given, without checking it against this list itself.
+var SortableUserColumns = map[string]bool{
+	"uuid":     true,
+	"username": true,
+	"age":      true,
+	"email":    true,
+	"phone":    true,
+	"createAt": true,
+}
+
+// FilterableUserColumns whitelists the columns ListUsers may filter on.
+var FilterableUserColumns = map[string]bool{
+	"username": true,
+	"email":    true,
+}
+
+// ListParams holds the pagination, sorting and filtering options accepted
+// by Store.ListUsers and Store.CountUsers.
+type ListParams struct {
+	Limit      int
+	Offset     int
+	SortColumn string
+	SortOrder  string
+	Filters    map[string]string
+}
@@ -6,291 +6,580 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
-	"github.com/google/uuid"
-	"github.com/jmoiron/sqlx"
+	"github.com/dyammarcano/exampleGoCRUD/internal/auth"
+	"github.com/dyammarcano/exampleGoCRUD/internal/database"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator/v10"
+	_ "github.com/go-sql-driver/mysql"
+	_ "github.com/lib/pq"
 	_ "github.com/mattn/go-sqlite3"
 	"io/fs"
 	"log"
 	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
 	"time"
 )
 
-const (
-	SqlCreateTable = `CREATE TABLE IF NOT EXISTS users (id INTEGER PRIMARY KEY AUTOINCREMENT, username TEXT, age INTEGER, email TEXT, phone TEXT, createAt TIMESTAMP);`
-	SqlCreateId    = `CREATE TABLE IF NOT EXISTS uuid_map (id INTEGER PRIMARY KEY AUTOINCREMENT, uuid TEXT, user_id TEXT, createAt TIMESTAMP DEFAULT CURRENT_TIMESTAMP, updateAt TIMESTAMP DEFAULT CURRENT_TIMESTAMP);`
-	SqlInsertUser  = `INSERT INTO users (username, age, email, phone, createAt) VALUES (?, ?, ?, ?, ?);`
-	SqlInsertId    = `INSERT INTO uuid_map (user_id, uuid) VALUES (?, ?);`
-	SqlSelectUsers = `SELECT * FROM users;`
-	SqlSeletUer    = `SELECT u.id, u.username, u.age, u.email, u.phone, u.createAt FROM users u JOIN uuid_map m ON u.id = m.user_id WHERE m.uuid = ?;`
-	SqlDeleteUser  = `DELETE FROM users WHERE id IN (SELECT user_id FROM uuid_map WHERE uuid = ?);`
-	SqlDeleteId    = `DELETE FROM uuid_map WHERE uuid = ?;`
-	SqlUpdateUser  = `UPDATE users SET username = ?, age = ?, email = ?, phone = ? WHERE id = ?;`
-)
+// authTokenTTL is how long an issued JWT access token remains valid.
+const authTokenTTL = 24 * time.Hour
+
+// userListMeta is the pagination metadata returned alongside GetUsersHandler's data.
+type userListMeta struct {
+	Total  int `json:"total"`
+	Limit  int `json:"limit"`
+	Offset int `json:"offset"`
+}
 
 //go:embed app/dist/*
 var content embed.FS
 
 type DataProvider struct {
-	*sqlx.DB
+	store      database.Store
+	authSecret []byte
 }
 
-// NewDataProvider creates a new data provider with the given driver name and data source name.
-func NewDataProvider(driverName, dataSourceName string) (*DataProvider, error) {
-	db, err := sqlx.Open(driverName, dataSourceName)
-	if err != nil {
-		return nil, err
+// isAdmin reports whether the caller authenticated by auth.Middleware holds the admin role.
+func isAdmin(r *http.Request) bool {
+	claims, ok := auth.FromContext(r.Context())
+	return ok && claims.Role == auth.RoleAdmin
+}
+
+// isSelfOrAdmin reports whether the caller is either an admin or the user identified by uid.
+func isSelfOrAdmin(r *http.Request, uid string) bool {
+	claims, ok := auth.FromContext(r.Context())
+	return ok && (claims.Role == auth.RoleAdmin || claims.UUID == uid)
+}
+
+// RegisterHandler creates a new account with a hashed password and the default "user" role.
+func (p *DataProvider) RegisterHandler(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Username string `json:"username"`
+		Email    string `json:"email"`
+		Phone    string `json:"phone"`
+		Age      int    `json:"age"`
+		Password string `json:"password"`
+	}
+
+	// decode the request body into req
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
 	}
-	if err = db.Ping(); err != nil {
-		return nil, err
+	if req.Password == "" {
+		respondWithError(w, http.StatusBadRequest, "password is required")
+		return
 	}
-	if _, err = db.Exec(SqlCreateTable); err != nil {
-		return nil, err
+
+	hash, err := auth.HashPassword(req.Password)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, err.Error())
+		return
 	}
-	if _, err = db.Exec(SqlCreateId); err != nil {
-		return nil, err
+
+	user := &database.User{
+		Username:     req.Username,
+		Email:        req.Email,
+		Phone:        req.Phone,
+		Age:          req.Age,
+		Role:         auth.RoleUser,
+		PasswordHash: hash,
+	}
+
+	if err = p.store.CreateUser(user); err != nil {
+		if errors.Is(err, database.ErrDuplicateEmail) {
+			respondWithError(w, http.StatusConflict, "email already registered")
+			return
+		}
+		respondWithError(w, http.StatusInternalServerError, err.Error())
+		return
 	}
-	return &DataProvider{
-		DB: db,
-	}, nil
-}
 
-type User struct {
-	ID       int64  `json:"-"`
-	UUID     string `json:"uuid"`
-	Username string `json:"username" db:"username"`
-	Email    string `json:"email" db:"email"`
-	Phone    string `json:"phone" db:"phone"`
-	Age      int    `json:"age" db:"age"`
-	CreateAt string `json:"createAt" db:"createAt"`
+	respondWithJSON(w, http.StatusCreated, user, nil)
 }
 
-func (p *DataProvider) AddUserHandler(w http.ResponseWriter, r *http.Request) {
-	// check if the request method is POST
-	if r.Method != http.MethodPost {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+// LoginHandler verifies email/password credentials and issues a JWT access token.
+func (p *DataProvider) LoginHandler(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Email    string `json:"email"`
+		Password string `json:"password"`
+	}
+
+	// decode the request body into req
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	// create a new user object with a unique uuid
-	user := &User{}
+	user, err := p.store.GetUserByEmail(req.Email)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			respondWithError(w, http.StatusUnauthorized, "invalid email or password")
+			return
+		}
+		respondWithError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
 
-	// decode the request body into user
-	if err := json.NewDecoder(r.Body).Decode(user); err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+	if err = auth.CheckPassword(user.PasswordHash, req.Password); err != nil {
+		respondWithError(w, http.StatusUnauthorized, "invalid email or password")
 		return
 	}
 
-	// insert hash_id into hash_id table
-	if err := createUser(p.DB, user); err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+	token, err := auth.GenerateToken(p.authSecret, user.UUID, user.Role, authTokenTTL)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, err.Error())
 		return
 	}
 
-	// encode the user to json and write it to the response writer
-	response(w, user)
+	respondWithJSON(w, http.StatusOK, map[string]string{"access_token": token}, nil)
 }
 
-func (p *DataProvider) GetUsersHandler(w http.ResponseWriter, r *http.Request) {
-	// check if the request method is GET
-	if r.Method != http.MethodGet {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+func (p *DataProvider) AddUserHandler(w http.ResponseWriter, r *http.Request) {
+	// only admins may create users directly; everyone else should register
+	if !isAdmin(r) {
+		respondWithError(w, http.StatusForbidden, "admin role required")
 		return
 	}
 
-	// query the users from the users table
-	rows, err := p.Queryx(SqlSelectUsers)
+	var req struct {
+		database.User
+		Password string `json:"password"`
+	}
+
+	// decode and validate the request body into req
+	if !decodeJSONStrict(w, r, &req) {
+		return
+	}
+	if req.Password == "" {
+		respondWithError(w, http.StatusBadRequest, "password is required")
+		return
+	}
+
+	hash, err := auth.HashPassword(req.Password)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		respondWithError(w, http.StatusInternalServerError, err.Error())
 		return
 	}
 
-	// create a slice of users
-	var users = make([]User, 0)
+	user := req.User
+	user.PasswordHash = hash
 
-	// iterate over the rows
-	for rows.Next() {
-		user := User{}
-		if err = rows.StructScan(&user); err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+	// insert the user into the users table
+	if err = p.store.CreateUser(&user); err != nil {
+		if errors.Is(err, database.ErrDuplicateEmail) {
+			respondWithError(w, http.StatusConflict, "email already registered")
 			return
 		}
-		users = append(users, user)
+		respondWithError(w, http.StatusInternalServerError, err.Error())
+		return
 	}
 
 	// encode the user to json and write it to the response writer
-	response(w, users)
+	respondWithJSON(w, http.StatusCreated, user, nil)
 }
 
-func (p *DataProvider) GetUserHandler(w http.ResponseWriter, r *http.Request) {
-	// check if the request method is GET
-	if r.Method != http.MethodGet {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+func (p *DataProvider) GetUsersHandler(w http.ResponseWriter, r *http.Request) {
+	// only admins may list all users
+	if !isAdmin(r) {
+		respondWithError(w, http.StatusForbidden, "admin role required")
+		return
+	}
+
+	// parse limit, offset, sort_column, sort_order and field filters
+	params, err := parseUserListParams(r.URL.Query())
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	// get the uuid from the query parameter
-	uid := r.URL.Query().Get("id")
+	// count the rows matching the filters, ignoring limit/offset
+	total, err := p.store.CountUsers(*params)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	// query the page of users matching the filters
+	users, err := p.store.ListUsers(*params)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	// encode the paged envelope and write it to the response writer
+	respondWithJSON(w, http.StatusOK, users, userListMeta{
+		Total:  total,
+		Limit:  params.Limit,
+		Offset: params.Offset,
+	})
+}
+
+func (p *DataProvider) GetUserHandler(w http.ResponseWriter, r *http.Request) {
+	// get the uuid from the URL path
+	uid := chi.URLParam(r, "uuid")
 	if uid == "" {
-		http.Error(w, "ID is required", http.StatusBadRequest)
+		respondWithError(w, http.StatusBadRequest, "ID is required")
+		return
+	}
+	if !isSelfOrAdmin(r, uid) {
+		respondWithError(w, http.StatusForbidden, "cannot read another user's account")
 		return
 	}
 
 	// create a new user object
-	user, err := getUserByUUID(p.DB, uid)
+	user, err := p.store.GetUser(uid)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
-			http.Error(w, fmt.Sprintf("User with ID %s not found", uid), http.StatusNotFound)
+			respondWithError(w, http.StatusNotFound, fmt.Sprintf("User with ID %s not found", uid))
 			return
 		}
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		respondWithError(w, http.StatusInternalServerError, err.Error())
 		return
 	}
 
 	// encode the user to json and write it to the response writer
-	response(w, user)
+	respondWithJSON(w, http.StatusOK, user, nil)
 }
 
 func (p *DataProvider) DeleteUserHandler(w http.ResponseWriter, r *http.Request) {
-	// check if the request method is DELETE
-	if r.Method != http.MethodDelete {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	// get the uuid from the URL path
+	uid := chi.URLParam(r, "uuid")
+	if uid == "" {
+		respondWithError(w, http.StatusBadRequest, "ID is required")
 		return
 	}
-
-	// get the id from the query parameter
-	id := r.URL.Query().Get("id")
-	if id == "" {
-		http.Error(w, "ID is required", http.StatusBadRequest)
+	if !isAdmin(r) {
+		respondWithError(w, http.StatusForbidden, "admin role required")
 		return
 	}
 
 	// delete the user from the users table
-	if err := deleteUser(p.DB, id); err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+	if err := p.store.DeleteUser(uid); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			respondWithError(w, http.StatusNotFound, fmt.Sprintf("User with ID %s not found", uid))
+			return
+		}
+		respondWithError(w, http.StatusInternalServerError, err.Error())
 		return
 	}
+
+	respondWithJSON(w, http.StatusOK, map[string]string{"uuid": uid}, nil)
 }
 
 func (p *DataProvider) UpdateUserHandler(w http.ResponseWriter, r *http.Request) {
-	// check if the request method is PUT
-	if r.Method != http.MethodPut {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	// get the uuid from the URL path
+	uid := chi.URLParam(r, "uuid")
+	if uid == "" {
+		respondWithError(w, http.StatusBadRequest, "ID is required")
+		return
+	}
+	if !isSelfOrAdmin(r, uid) {
+		respondWithError(w, http.StatusForbidden, "cannot update another user's account")
 		return
 	}
 
 	// create a new user object
-	var user User
+	var user database.User
 
-	// decode the request body into user
-	if err := json.NewDecoder(r.Body).Decode(&user); err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+	// decode and validate the request body into user
+	if !decodeJSONStrict(w, r, &user) {
 		return
 	}
 
+	// the path, not the body, is authoritative for which user is updated
+	user.UUID = uid
+
 	// update the user in the users table
-	if err := updateUser(p.DB, &user); err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+	if err := p.store.UpdateUser(&user); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			respondWithError(w, http.StatusNotFound, fmt.Sprintf("User with ID %s not found", uid))
+			return
+		}
+		if errors.Is(err, database.ErrDuplicateEmail) {
+			respondWithError(w, http.StatusConflict, "email already registered")
+			return
+		}
+		respondWithError(w, http.StatusBadRequest, err.Error())
 		return
 	}
 
 	// encode the user to json and write it to the response writer
-	response(w, user)
+	respondWithJSON(w, http.StatusOK, user, nil)
+}
+
+// dbConfig is the driver/DSN pair used to open the Store, sourced from
+// environment variables so the same binary can target SQLite in dev and
+// MySQL or Postgres in production.
+type dbConfig struct {
+	driver string
+	dsn    string
+}
+
+// loadDBConfig reads DB_DRIVER/DB_DSN from the environment, defaulting to a
+// local SQLite file so `go run .` keeps working out of the box.
+func loadDBConfig() dbConfig {
+	cfg := dbConfig{
+		driver: "sqlite3",
+		dsn:    "file:users.sqlite3?cache=shared&_foreign_keys=1",
+	}
+
+	if driver := os.Getenv("DB_DRIVER"); driver != "" {
+		cfg.driver = driver
+	}
+	if dsn := os.Getenv("DB_DSN"); dsn != "" {
+		cfg.dsn = dsn
+	}
+
+	return cfg
+}
+
+// seedInitialAdmin creates the first admin account from ADMIN_EMAIL/
+// ADMIN_PASSWORD if they're set, so the admin-gated endpoints are reachable
+// on a fresh deploy instead of returning 403 forever. It's safe to call on
+// every startup: once the account exists, CreateUser's duplicate-email
+// check makes this a no-op.
+func seedInitialAdmin(store database.Store) error {
+	email := os.Getenv("ADMIN_EMAIL")
+	password := os.Getenv("ADMIN_PASSWORD")
+	if email == "" || password == "" {
+		return nil
+	}
+
+	username := os.Getenv("ADMIN_USERNAME")
+	if username == "" {
+		username = "admin"
+	}
+
+	hash, err := auth.HashPassword(password)
+	if err != nil {
+		return err
+	}
+
+	user := &database.User{
+		Username:     username,
+		Email:        email,
+		Role:         auth.RoleAdmin,
+		PasswordHash: hash,
+	}
+
+	if err = store.CreateUser(user); err != nil {
+		if errors.Is(err, database.ErrDuplicateEmail) {
+			return nil
+		}
+		return err
+	}
+
+	log.Printf("seeded initial admin account %q", email)
+	return nil
 }
 
 func main() {
-	provider, err := NewDataProvider("sqlite3", "file:users.sqlite3?cache=shared&_foreign_keys=1")
+	authSecret := []byte(os.Getenv("AUTH_JWT_SECRET"))
+	if len(authSecret) == 0 {
+		log.Println("AUTH_JWT_SECRET not set, falling back to an insecure dev secret")
+		authSecret = []byte("dev-secret-change-me")
+	}
+
+	dbCfg := loadDBConfig()
+	store, err := database.Open(dbCfg.driver, dbCfg.dsn)
 	if err != nil {
 		log.Fatal(err)
 	}
-	defer provider.Close()
+	defer store.Close()
+
+	if err = seedInitialAdmin(store); err != nil {
+		log.Fatal(err)
+	}
 
-	mux := http.NewServeMux()
+	provider := &DataProvider{store: store, authSecret: authSecret}
 
-	mux.HandleFunc("/user/add", provider.AddUserHandler)
-	mux.HandleFunc("/user/get", provider.GetUserHandler)
-	mux.HandleFunc("/user/delete", provider.DeleteUserHandler)
-	mux.HandleFunc("/user/list", provider.GetUsersHandler)
-	mux.HandleFunc("/user/update", provider.UpdateUserHandler)
+	router := chi.NewRouter()
+
+	router.Post("/auth/register", provider.RegisterHandler)
+	router.Post("/auth/login", provider.LoginHandler)
+
+	router.Route("/api/v1/users", func(r chi.Router) {
+		r.Use(auth.Middleware(provider.authSecret))
+		r.Get("/", provider.GetUsersHandler)
+		r.Post("/", provider.AddUserHandler)
+		r.Get("/{uuid}", provider.GetUserHandler)
+		r.Put("/{uuid}", provider.UpdateUserHandler)
+		r.Delete("/{uuid}", provider.DeleteUserHandler)
+	})
 
 	// frontend layer
-	mux.Handle("/", http.StripPrefix("/", assetsHandler(content)))
+	router.Handle("/*", http.StripPrefix("/", assetsHandler(content)))
 
 	log.Println("Server is running on port 8080")
 
-	log.Fatal(http.ListenAndServe(":8080", mux))
+	log.Fatal(http.ListenAndServe(":8080", router))
+}
+
+// dataEnvelope is the JSON shape every successful response is wrapped in,
+// so clients always get the payload under "data" and any pagination or
+// other side information under "meta".
+type dataEnvelope struct {
+	Data any `json:"data"`
+	Meta any `json:"meta,omitempty"`
+}
+
+// fieldError is a single per-field validation failure.
+type fieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// errorEnvelope is the JSON shape every failed response is wrapped in.
+type errorEnvelope struct {
+	Error  string       `json:"error"`
+	Fields []fieldError `json:"fields,omitempty"`
 }
 
-// response writes the user to the response writer.
-func response(w http.ResponseWriter, v any) {
-	// set header content type to application/json
+// respondWithJSON writes data (and optional meta) to w as a dataEnvelope.
+func respondWithJSON(w http.ResponseWriter, code int, data any, meta any) {
 	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
 
-	// encode the user to json and write it to the response writer
-	if err := json.NewEncoder(w).Encode(v); err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
-		return
+	if err := json.NewEncoder(w).Encode(dataEnvelope{Data: data, Meta: meta}); err != nil {
+		log.Printf("failed to encode response: %v", err)
 	}
 }
 
-func createUser(db *sqlx.DB, user *User) error {
-	// Generate a new UUID and assign it to the user
-	user.UUID = uuid.New().String()
-	user.CreateAt = time.Now().Format(time.RFC3339)
+// respondWithError writes msg to w as an errorEnvelope.
+func respondWithError(w http.ResponseWriter, code int, msg string) {
+	respondWithValidationError(w, code, msg, nil)
+}
 
-	// Get the last inserted ID (user_id)
-	result, err := db.Exec(SqlInsertUser, user.Username, user.Age, user.Email, user.Phone, user.CreateAt)
-	if err != nil {
-		return err
-	}
+// respondWithValidationError writes msg and its per-field validation
+// failures to w as an errorEnvelope.
+func respondWithValidationError(w http.ResponseWriter, code int, msg string, fields []fieldError) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
 
-	// Get the last inserted ID (user_id)
-	userID, err := result.LastInsertId()
-	if err != nil {
-		return err
+	if err := json.NewEncoder(w).Encode(errorEnvelope{Error: msg, Fields: fields}); err != nil {
+		log.Printf("failed to encode error response: %v", err)
 	}
-
-	// Update uuid_map table with user_id
-	_, err = db.Exec(SqlInsertId, userID, user.UUID)
-	return err
 }
 
-func getUserByUUID(db *sqlx.DB, uuid string) (*User, error) {
-	// get the user from the users table
-	var user User
+// maxRequestBodyBytes caps the size of a request body accepted by decodeJSONStrict.
+const maxRequestBodyBytes = 1 << 20 // 1MB
+
+// validate runs the `validate` struct tags declared on database.User.
+var validate = validator.New()
+
+// decodeJSONStrict decodes r.Body into dst, rejecting unknown fields and
+// bodies over maxRequestBodyBytes, then validates dst against its struct
+// tags. On failure it writes the appropriate error response itself and
+// returns false.
+func decodeJSONStrict(w http.ResponseWriter, r *http.Request, dst any) bool {
+	r.Body = http.MaxBytesReader(w, r.Body, maxRequestBodyBytes)
 
-	// get the user from the users table
-	if err := db.Get(&user, SqlSeletUer, uuid); err != nil {
-		return nil, err
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+
+	if err := decoder.Decode(dst); err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return false
 	}
 
-	user.UUID = uuid
+	if err := validate.Struct(dst); err != nil {
+		var validationErrs validator.ValidationErrors
+		if errors.As(err, &validationErrs) {
+			respondWithValidationError(w, http.StatusBadRequest, "validation failed", fieldErrorsFrom(validationErrs))
+			return false
+		}
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return false
+	}
 
-	return &user, nil
+	return true
 }
 
-func updateUser(db *sqlx.DB, user *User) error {
-	// get the user from the users table
-	result, err := getUserByUUID(db, user.UUID)
-	if err != nil {
-		return err
+// fieldErrorsFrom translates validator.ValidationErrors into the API's fieldError shape.
+func fieldErrorsFrom(validationErrs validator.ValidationErrors) []fieldError {
+	fields := make([]fieldError, 0, len(validationErrs))
+	for _, fe := range validationErrs {
+		fields = append(fields, fieldError{Field: fe.Field(), Message: validationMessage(fe)})
 	}
-	// update the user in the users table
-	if _, err = db.Exec(SqlUpdateUser, user.Username, user.Age, user.Email, user.Phone, result.ID); err != nil {
-		return err
+	return fields
+}
+
+// validationMessage renders a human-readable message for a single validator.FieldError.
+func validationMessage(fe validator.FieldError) string {
+	switch fe.Tag() {
+	case "required":
+		return "is required"
+	case "email":
+		return "must be a valid email address"
+	case "e164":
+		return "must be a valid E.164 phone number"
+	case "gte":
+		return fmt.Sprintf("must be >= %s", fe.Param())
+	case "lte":
+		return fmt.Sprintf("must be <= %s", fe.Param())
+	case "min":
+		return fmt.Sprintf("must be at least %s characters", fe.Param())
+	case "max":
+		return fmt.Sprintf("must be at most %s characters", fe.Param())
+	default:
+		return fmt.Sprintf("failed %q validation", fe.Tag())
 	}
-	return err
 }
 
-func deleteUser(db *sqlx.DB, uid string) error {
-	// delete the user from the users table
-	if _, err := db.Exec(SqlDeleteUser, uid); err != nil {
-		return err
+// parseUserListParams validates the limit/offset/sort/filter query
+// parameters used by GetUsersHandler.
+func parseUserListParams(values url.Values) (*database.ListParams, error) {
+	params := &database.ListParams{
+		Limit:      database.DefaultListLimit,
+		Offset:     0,
+		SortColumn: "createAt",
+		SortOrder:  "ASC",
+		Filters:    make(map[string]string),
 	}
-	// delete the id from the hash_id table
-	if _, err := db.Exec(SqlDeleteId, uid); err != nil {
-		return err
+
+	if v := values.Get("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil || limit <= 0 {
+			return nil, fmt.Errorf("limit must be a positive integer")
+		}
+		if limit > database.MaxListLimit {
+			limit = database.MaxListLimit
+		}
+		params.Limit = limit
 	}
-	return nil
+
+	if v := values.Get("offset"); v != "" {
+		offset, err := strconv.Atoi(v)
+		if err != nil || offset < 0 {
+			return nil, fmt.Errorf("offset must be a non-negative integer")
+		}
+		params.Offset = offset
+	}
+
+	if v := values.Get("sort_column"); v != "" {
+		if !database.SortableUserColumns[v] {
+			return nil, fmt.Errorf("sort_column %q is not sortable", v)
+		}
+		params.SortColumn = v
+	}
+
+	if v := strings.ToUpper(values.Get("sort_order")); v != "" {
+		if v != "ASC" && v != "DESC" {
+			return nil, fmt.Errorf("sort_order must be ASC or DESC")
+		}
+		params.SortOrder = v
+	}
+
+	for column := range database.FilterableUserColumns {
+		if v := values.Get(column); v != "" {
+			params.Filters[column] = v
+		}
+	}
+
+	return params, nil
 }
 
 func assetsHandler(content embed.FS) http.HandlerFunc {
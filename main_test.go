@@ -0,0 +1,88 @@
+package main
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/dyammarcano/exampleGoCRUD/internal/database"
+)
+
+func TestParseUserListParams_Defaults(t *testing.T) {
+	params, err := parseUserListParams(url.Values{})
+	if err != nil {
+		t.Fatalf("parseUserListParams: %v", err)
+	}
+	if params.Limit != database.DefaultListLimit {
+		t.Errorf("Limit = %d, want %d", params.Limit, database.DefaultListLimit)
+	}
+	if params.Offset != 0 {
+		t.Errorf("Offset = %d, want 0", params.Offset)
+	}
+	if params.SortColumn != "createAt" {
+		t.Errorf("SortColumn = %q, want %q", params.SortColumn, "createAt")
+	}
+	if params.SortOrder != "ASC" {
+		t.Errorf("SortOrder = %q, want %q", params.SortOrder, "ASC")
+	}
+}
+
+func TestParseUserListParams_LimitCap(t *testing.T) {
+	values := url.Values{"limit": {"999999"}}
+	params, err := parseUserListParams(values)
+	if err != nil {
+		t.Fatalf("parseUserListParams: %v", err)
+	}
+	if params.Limit != database.MaxListLimit {
+		t.Errorf("Limit = %d, want capped at %d", params.Limit, database.MaxListLimit)
+	}
+}
+
+func TestParseUserListParams_Invalid(t *testing.T) {
+	tests := []struct {
+		name   string
+		values url.Values
+	}{
+		{"negative limit", url.Values{"limit": {"-1"}}},
+		{"non-numeric limit", url.Values{"limit": {"abc"}}},
+		{"negative offset", url.Values{"offset": {"-1"}}},
+		{"unsortable column", url.Values{"sort_column": {"password_hash"}}},
+		{"invalid sort order", url.Values{"sort_order": {"SIDEWAYS"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := parseUserListParams(tt.values); err == nil {
+				t.Error("expected an error, got nil")
+			}
+		})
+	}
+}
+
+func TestParseUserListParams_SortColumnWhitelist(t *testing.T) {
+	params, err := parseUserListParams(url.Values{"sort_column": {"email"}, "sort_order": {"desc"}})
+	if err != nil {
+		t.Fatalf("parseUserListParams: %v", err)
+	}
+	if params.SortColumn != "email" {
+		t.Errorf("SortColumn = %q, want %q", params.SortColumn, "email")
+	}
+	if params.SortOrder != "DESC" {
+		t.Errorf("SortOrder = %q, want %q", params.SortOrder, "DESC")
+	}
+}
+
+func TestParseUserListParams_Filters(t *testing.T) {
+	params, err := parseUserListParams(url.Values{
+		"email":         {"a@example.com"},
+		"password_hash": {"ignored"},
+	})
+	if err != nil {
+		t.Fatalf("parseUserListParams: %v", err)
+	}
+	if params.Filters["email"] != "a@example.com" {
+		t.Errorf("Filters[email] = %q, want %q", params.Filters["email"], "a@example.com")
+	}
+	if _, ok := params.Filters["password_hash"]; ok {
+		t.Error("Filters contains non-whitelisted column password_hash")
+	}
+}